@@ -0,0 +1,449 @@
+package uutid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// gregorianToUnixOffset100ns is the number of 100-ns intervals between the
+// start of the Gregorian calendar (1582-10-15 00:00:00 UTC) and the Unix
+// epoch. It is the standard offset used to convert RFC 4122 v1/v6 60-bit
+// timestamps to and from time.Time.
+const gregorianToUnixOffset100ns = 122192928000000000
+
+// Generator produces UUTIDs from its own random source, clock, and
+// version, independent of any other Generator. This makes it safe to run
+// several differently-configured generators concurrently in one process,
+// unlike the package-level SetRand/SetVersion functions which mutate
+// shared state.
+//
+// The zero value is not ready to use; construct one with NewGenerator.
+type Generator struct {
+	mu sync.Mutex
+
+	rand    io.Reader
+	version int
+	clock   func() time.Time
+
+	monotonic bool
+	v7LastMs  int64
+	v7Counter uint16
+
+	nodeID      [6]byte
+	nodeIDSet   bool
+	clockSeq    uint16
+	clockSeqSet bool
+	hwAddrFunc  func() (net.HardwareAddr, error)
+}
+
+// Option configures a Generator constructed by NewGenerator.
+type Option func(*Generator)
+
+// WithRand sets the random source a Generator reads entropy from. Passing
+// nil resets it to the default (crypto/rand.Reader).
+func WithRand(r io.Reader) Option {
+	return func(g *Generator) {
+		if r == nil {
+			r = rand.Reader
+		}
+		g.rand = r
+	}
+}
+
+// WithVersion sets the UUID version a Generator's New/NewWithTime produce.
+// Versions 3, 5, and 6 have dedicated, non-generic layouts that New cannot
+// produce (use NewV3, NewV5, and NewV6 instead), so v is ignored if it is
+// one of those, or outside the valid 0-9 range.
+func WithVersion(v int) Option {
+	return func(g *Generator) {
+		if v < 0 || v > 9 || v == 3 || v == 5 || v == 6 {
+			return
+		}
+		g.version = v
+	}
+}
+
+// WithClock overrides the clock a Generator uses for New, NewV6, and
+// NewV7 (NewWithTime/NewV6WithTime/NewV7WithTime always take an explicit
+// time.Time and ignore the clock). Passing nil resets it to time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(g *Generator) {
+		if clock == nil {
+			clock = time.Now
+		}
+		g.clock = clock
+	}
+}
+
+// WithNodeID sets the 6-byte node ID a Generator embeds in v6 UUTIDs,
+// overriding hardware address resolution. Slices shorter than 6 bytes are
+// ignored.
+func WithNodeID(id []byte) Option {
+	return func(g *Generator) {
+		if len(id) < 6 {
+			return
+		}
+		copy(g.nodeID[:], id[:6])
+		g.nodeIDSet = true
+	}
+}
+
+// WithMonotonic controls whether a Generator's v7 UUTIDs carry a
+// monotonically increasing per-millisecond counter (the default). Disabling
+// it makes every v7 UUTID generated within the same millisecond carry a
+// zero counter.
+func WithMonotonic(enabled bool) Option {
+	return func(g *Generator) {
+		g.monotonic = enabled
+	}
+}
+
+// NewGenerator returns a ready-to-use Generator configured with opts. Its
+// defaults match the package-level New: crypto/rand entropy, version 4,
+// time.Now as the clock, and a monotonic v7 counter.
+func NewGenerator(opts ...Option) *Generator {
+	g := &Generator{
+		rand:       rand.Reader,
+		version:    4,
+		clock:      time.Now,
+		monotonic:  true,
+		hwAddrFunc: defaultHWAddrFunc,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// DefaultGenerator is the Generator backing the package-level New,
+// NewWithTime, NewV6, NewV6WithTime, NewV7, NewV7WithTime, SetRand,
+// SetVersion, SetNodeID, and SetHWAddrFunc functions.
+var DefaultGenerator = NewGenerator()
+
+// SetRand sets the random number generator used by the DefaultGenerator.
+// Calling with nil will set the random number generator to the default (crypto/rand).
+// The default, rand.Reader from crypto/rand, is cryptographically secure.
+//
+// For concurrent-safe, independently configured generation use NewGenerator instead.
+func SetRand(r io.Reader) {
+	DefaultGenerator.mu.Lock()
+	defer DefaultGenerator.mu.Unlock()
+
+	if r == nil {
+		r = rand.Reader
+	}
+	DefaultGenerator.rand = r
+}
+
+// SetVersion sets the UUID version used by the DefaultGenerator.
+//
+// Versions 3, 5, and 6 have dedicated, non-generic layouts that New
+// cannot produce; use NewV3, NewV5, and NewV6 instead of setting one of
+// those versions here.
+//
+// For concurrent-safe, independently configured generation use NewGenerator instead.
+func SetVersion(v int) error {
+	if v < 0 || v > 9 {
+		return errors.New("version must be a positive integer smaller than 10")
+	}
+	if v == 3 || v == 5 || v == 6 {
+		return errors.New("version 3, 5, and 6 UUTIDs must be created with NewV3, NewV5, or NewV6")
+	}
+
+	DefaultGenerator.mu.Lock()
+	defer DefaultGenerator.mu.Unlock()
+
+	DefaultGenerator.version = v
+
+	return nil
+}
+
+// SetNodeID overrides the 6-byte node ID the DefaultGenerator embeds in
+// v6 UUTIDs. Only the first 6 bytes of id are used; shorter slices are
+// ignored.
+func SetNodeID(id []byte) {
+	if len(id) < 6 {
+		return
+	}
+
+	DefaultGenerator.mu.Lock()
+	defer DefaultGenerator.mu.Unlock()
+
+	copy(DefaultGenerator.nodeID[:], id[:6])
+	DefaultGenerator.nodeIDSet = true
+}
+
+// SetHWAddrFunc overrides the function the DefaultGenerator uses to
+// resolve a hardware address for the v6 node ID. Passing nil disables
+// hardware address resolution and falls back to a random node ID with the
+// multicast bit set.
+func SetHWAddrFunc(f func() (net.HardwareAddr, error)) {
+	DefaultGenerator.mu.Lock()
+	defer DefaultGenerator.mu.Unlock()
+
+	DefaultGenerator.hwAddrFunc = f
+	DefaultGenerator.nodeIDSet = false
+}
+
+// New return a UUTID that looks like a UUID but is not directly compatible with UUID.
+// UUTID can be converted to any UUID type.
+func New() UUTID {
+	return DefaultGenerator.New()
+}
+
+// NewWithTime is used by New which uses time.Now() as t
+func NewWithTime(t time.Time) UUTID {
+	return DefaultGenerator.NewWithTime(t)
+}
+
+// NewV7 returns a UUTID laid out per the draft-peabody UUIDv7 format: a
+// 48-bit big-endian millisecond Unix timestamp, a 12-bit per-millisecond
+// monotonic counter, and 62 bits of randomness.
+func NewV7() UUTID {
+	return DefaultGenerator.NewV7()
+}
+
+// NewV7WithTime is used by NewV7 which uses time.Now() as t
+func NewV7WithTime(t time.Time) UUTID {
+	return DefaultGenerator.NewV7WithTime(t)
+}
+
+// NewV6 returns a UUTID in the k-sortable v6 layout: a 60-bit Gregorian
+// timestamp (100-ns intervals since 1582-10-15 UTC), a 14-bit clock
+// sequence, and a node ID (the host's MAC address by default). This is a
+// field-compatible, sortable replacement for v1 UUIDs.
+func NewV6() UUTID {
+	return DefaultGenerator.NewV6()
+}
+
+// NewV6WithTime is used by NewV6 which uses time.Now() as t
+func NewV6WithTime(t time.Time) UUTID {
+	return DefaultGenerator.NewV6WithTime(t)
+}
+
+// New returns a UUTID using g's version, random source, and clock.
+func (g *Generator) New() UUTID {
+	return g.NewWithTime(g.clock())
+}
+
+// NewWithTime is used by New which uses g's clock as t
+func (g *Generator) NewWithTime(t time.Time) UUTID {
+	g.mu.Lock()
+	v := g.version
+	r := g.rand
+	g.mu.Unlock()
+
+	if v == 7 {
+		return g.NewV7WithTime(t)
+	}
+
+	var uutid UUTID
+
+	sec := t.Unix()
+	nsec := t.Nanosecond()
+
+	// Shift left to get the most of the high part of the nanoseconds in the first 16bit
+	// This is to make room for the version
+	// The first 2 bits will never be used anyway as its > 999999999
+	nsec = nsec << 2
+
+	// Extract the first and highest part of the nanoseconds
+	ns1 := nsec >> 16 & 0xffff
+
+	// Extract the lowest part of the nanoseconds
+	ns2 := nsec & 0xffff
+
+	// Utilize the four zeros in the lowest bits
+	ns2 = (ns2 >> 4) & 0x0fff
+
+	// Set the version in the last part of the timestamp
+	// ns2 |= 0x4000 // Version 4
+	ns2 |= v << 12 // e.g. 0x4000
+
+	// Write the timestamp and version to the uutid
+	binary.BigEndian.PutUint32(uutid[0:4], uint32(sec))
+	binary.BigEndian.PutUint16(uutid[4:6], uint16(ns1))
+	binary.BigEndian.PutUint16(uutid[6:8], uint16(ns2))
+
+	// Fill the rest of the uutid with randomness
+	_, err := io.ReadFull(r, uutid[8:])
+	if err != nil {
+		return NilUUTID
+	}
+
+	// Finally set the variant to 1 (big endianness)
+	uutid[8] = (uutid[8] & 0x3f) | 0x80
+
+	return uutid
+}
+
+// NewV7 returns a v7 UUTID using g's random source and clock.
+func (g *Generator) NewV7() UUTID {
+	return g.NewV7WithTime(g.clock())
+}
+
+// NewV7WithTime is used by NewV7 which uses g's clock as t
+func (g *Generator) NewV7WithTime(t time.Time) UUTID {
+	var uutid UUTID
+
+	ms, counter := g.nextV7(t)
+
+	var msBuf [8]byte
+	binary.BigEndian.PutUint64(msBuf[:], uint64(ms))
+	copy(uutid[0:6], msBuf[2:8])
+
+	// Version 0x7 in the high nibble, monotonic counter in the low 12 bits
+	uutid[6] = 0x70 | byte(counter>>8&0x0f)
+	uutid[7] = byte(counter & 0xff)
+
+	g.mu.Lock()
+	r := g.rand
+	g.mu.Unlock()
+
+	// Fill the rest of the uutid with randomness
+	_, err := io.ReadFull(r, uutid[8:])
+	if err != nil {
+		return NilUUTID
+	}
+
+	// Set the variant to 1 (big endianness)
+	uutid[8] = (uutid[8] & 0x3f) | 0x80
+
+	return uutid
+}
+
+// nextV7 returns the millisecond timestamp and monotonic counter to use
+// for a v7 UUTID generated "at" t. When two calls land in the same
+// millisecond the counter is incremented; on overflow the timestamp is
+// moved on to the next millisecond and the counter restarts at zero,
+// without waiting for t or the clock to reach that millisecond. If t goes
+// backwards relative to the
+// last observed timestamp, the last observed millisecond is reused so the
+// sequence never goes backwards. If g.monotonic is false, every call is
+// treated as a new millisecond with a zero counter.
+func (g *Generator) nextV7(t time.Time) (int64, uint16) {
+	ms := t.UnixMilli()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.monotonic {
+		return ms, 0
+	}
+
+	if ms < g.v7LastMs {
+		ms = g.v7LastMs
+	}
+
+	if ms == g.v7LastMs {
+		g.v7Counter++
+		if g.v7Counter > 0x0fff {
+			ms++
+			g.v7Counter = 0
+		}
+	} else {
+		g.v7Counter = 0
+	}
+
+	g.v7LastMs = ms
+
+	return ms, g.v7Counter
+}
+
+// NewV6 returns a v6 UUTID using g's random source, clock, and node ID.
+func (g *Generator) NewV6() UUTID {
+	return g.NewV6WithTime(g.clock())
+}
+
+// NewV6WithTime is used by NewV6 which uses g's clock as t
+func (g *Generator) NewV6WithTime(t time.Time) UUTID {
+	var uutid UUTID
+
+	ticks := uint64(t.UnixNano())/100 + gregorianToUnixOffset100ns
+
+	high := uint32(ticks >> 28)
+	mid := uint16((ticks >> 12) & 0xffff)
+	low := uint16(ticks&0x0fff) | 0x6000 // low 12 bits of the timestamp, version 0x6 in the high nibble
+
+	binary.BigEndian.PutUint32(uutid[0:4], high)
+	binary.BigEndian.PutUint16(uutid[4:6], mid)
+	binary.BigEndian.PutUint16(uutid[6:8], low)
+
+	seq := g.clockSeqValue()
+	uutid[8] = 0x80 | byte(seq>>8&0x3f) // variant 10, high 6 bits of clock sequence
+	uutid[9] = byte(seq & 0xff)
+
+	node := g.nodeIDValue()
+	copy(uutid[10:], node[:])
+
+	return uutid
+}
+
+// nodeIDValue returns the node ID to embed in a v6 UUTID, resolving it
+// from hwAddrFunc on first use. If no hardware address is available, a
+// random node ID is generated with the multicast bit set, per RFC 4122
+// §4.1.6.
+func (g *Generator) nodeIDValue() [6]byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.nodeIDSet {
+		return g.nodeID
+	}
+
+	if g.hwAddrFunc != nil {
+		if hw, err := g.hwAddrFunc(); err == nil && len(hw) >= 6 {
+			copy(g.nodeID[:], hw[:6])
+			g.nodeIDSet = true
+			return g.nodeID
+		}
+	}
+
+	io.ReadFull(g.rand, g.nodeID[:])
+	g.nodeID[0] |= 0x01 // multicast bit
+	g.nodeIDSet = true
+
+	return g.nodeID
+}
+
+// clockSeqValue returns the 14-bit clock sequence to embed in a v6 UUTID,
+// generating it once at random on first use.
+func (g *Generator) clockSeqValue() uint16 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.clockSeqSet {
+		return g.clockSeq
+	}
+
+	var buf [2]byte
+	io.ReadFull(g.rand, buf[:])
+	g.clockSeq = binary.BigEndian.Uint16(buf[:]) & 0x3fff
+	g.clockSeqSet = true
+
+	return g.clockSeq
+}
+
+// defaultHWAddrFunc returns the hardware address of the first network
+// interface that has one.
+func defaultHWAddrFunc() (net.HardwareAddr, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) >= 6 {
+			return iface.HardwareAddr, nil
+		}
+	}
+
+	return nil, errors.New("uutid: no hardware address available")
+}
@@ -0,0 +1,60 @@
+package uutid
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+)
+
+// Standard namespaces defined by RFC 4122 §4.3, for use with NewV3/NewV5.
+var (
+	NamespaceDNS  = mustFromUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceURL  = mustFromUUID("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceOID  = mustFromUUID("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceX500 = mustFromUUID("6ba7b814-9dad-11d1-80b4-00c04fd430c8")
+)
+
+func mustFromUUID(uuid string) UUTID {
+	ns, err := FromUUID(uuid)
+	if err != nil {
+		panic(err)
+	}
+	return ns
+}
+
+// NewV3 returns a name-based UUTID derived from ns and name per RFC 4122
+// §4.3: MD5(ns || name), with the version nibble set to 0x3 and the
+// variant bits set to RFC 4122. Identical ns/name pairs always produce the
+// same UUTID, which makes v3 useful for content-addressed records and
+// idempotency tokens.
+func NewV3(ns UUTID, name []byte) UUTID {
+	h := md5.New()
+	h.Write(ns[:])
+	h.Write(name)
+
+	return newFromNameHash(h.Sum(nil), 0x30)
+}
+
+// NewV5 returns a name-based UUTID derived from ns and name per RFC 4122
+// §4.3: SHA-1(ns || name), with the version nibble set to 0x5 and the
+// variant bits set to RFC 4122. Prefer NewV5 over NewV3 unless MD5 is
+// required for compatibility with an existing system.
+func NewV5(ns UUTID, name []byte) UUTID {
+	h := sha1.New()
+	h.Write(ns[:])
+	h.Write(name)
+
+	return newFromNameHash(h.Sum(nil), 0x50)
+}
+
+// newFromNameHash takes the first 16 bytes of a name-based hash and
+// overwrites the version nibble (byte 6) and variant bits (byte 8) per
+// RFC 4122 §4.3.
+func newFromNameHash(sum []byte, version byte) UUTID {
+	var uutid UUTID
+	copy(uutid[:], sum[:16])
+
+	uutid[6] = (uutid[6] & 0x0f) | version
+	uutid[8] = (uutid[8] & 0x3f) | 0x80
+
+	return uutid
+}
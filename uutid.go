@@ -1,102 +1,30 @@
 package uutid
 
 import (
-	"crypto/rand"
+	"database/sql/driver"
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"io"
+	"strings"
 	"time"
 )
 
 type UUTID [16]byte
 
-var (
-	// version is the UUID version to use
-	version = 4
+// NilUUTID is an empty UUTID, all zeros
+var NilUUTID UUTID
 
-	// math/rand is faster than crypto/rand, but not cryptographically secure
-	// rander = io.Reader(rand.New(rand.NewSource(int64(time.Now().UnixNano()))))
-	rander = rand.Reader
-
-	// NilUUTID is an empty UUTID, all zeros
-	NilUUTID UUTID
-)
-
-// SetRand sets the random number generator.
-// Calling with nil will set the random number generator to the default (math/rand).
-// For slower but cryptographically secure randomness, use rand.Reader from crypto/rand.
-func SetRand(r io.Reader) {
-	if r == nil {
-		rander = rand.Reader
-		return
-	}
-	rander = r
-}
-
-// SetVersion set the UUID version to use
-func SetVersion(v int) error {
-	if v < 0 || v > 9 {
-		return errors.New("version must be a positive integer smaller than 10")
-	}
-	version = v
-	return nil
-}
-
-// New return a UUTID that looks like a UUID but is not directly compatible with UUID.
-// UUTID can be converted to any UUID type.
-func New() UUTID {
-	return NewWithTime(time.Now())
-}
-
-// NewWithTime is used by New which uses time.Now() as t
-func NewWithTime(t time.Time) UUTID {
-	var uutid UUTID
-
-	sec := t.Unix()
-	nsec := t.Nanosecond()
-
-	// Shift left to get the most of the high part of the nanoseconds in the first 16bit
-	// This is to make room for the version
-	// The first 2 bits will never be used anyway as its > 999999999
-	nsec = nsec << 2
-
-	// Extract the first and highest part of the nanoseconds
-	ns1 := nsec >> 16 & 0xffff
-
-	// Extract the lowest part of the nanoseconds
-	ns2 := nsec & 0xffff
-
-	// Utilize the four zeros in the lowest bits
-	ns2 = (ns2 >> 4) & 0x0fff
-
-	// Set the version in the last part of the timestamp
-	// ns2 |= 0x4000 // Version 4
-	ns2 |= version << 12 // e.g. 0x4000
-
-	// Write the timestamp and version to the uutid
-	binary.BigEndian.PutUint32(uutid[0:4], uint32(sec))
-	binary.BigEndian.PutUint16(uutid[4:6], uint16(ns1))
-	binary.BigEndian.PutUint16(uutid[6:8], uint16(ns2))
-
-	// Fill the rest of the uutid with randomness
-	_, err := io.ReadFull(rander, uutid[8:])
-	if err != nil {
-		return NilUUTID
-	}
-
-	// Finally set the variant to 1 (big endianness)
-	uutid[8] = (uutid[8] & 0x3f) | 0x80
-
-	return uutid
-}
-
-// FromBytes converts a byte slice to a UUTID
-func FromBytes(uutidSlice []byte) UUTID {
+// FromBytes converts a byte slice to a UUTID.
+//
+// v2 note: FromBytes now returns an error for a slice that isn't exactly
+// 16 bytes long, instead of silently returning NilUUTID as it did in v1.
+// This is why the module's import path carries a /v2 suffix.
+func FromBytes(uutidSlice []byte) (UUTID, error) {
 	if len(uutidSlice) != 16 {
-		return NilUUTID
+		return NilUUTID, errors.New("unable to extract uutid from byte slice")
 	}
 
 	return UUTID{
@@ -116,7 +44,7 @@ func FromBytes(uutidSlice []byte) UUTID {
 		13: uutidSlice[13],
 		14: uutidSlice[14],
 		15: uutidSlice[15],
-	}
+	}, nil
 }
 
 // FromBase64 returns uutid from a base 64 encoded uutid
@@ -131,22 +59,167 @@ func FromBase64(str string) (UUTID, error) {
 	return uutid, nil
 }
 
-// FromBase32 returns uutid from a base 32 encoded uutid
+// FromBase32 returns uutid from a Crockford base 32 (ULID-style) encoded
+// uutid. Decoding is case-insensitive and normalises the ambiguous glyphs
+// I/L to 1 and O to 0, per the Crockford spec. The canonical 26-char form
+// uses the same 128-bit bit layout as ULID (two padding bits followed by
+// the 128-bit value, encoded 5 bits at a time), so strings produced by
+// this package and by other ULID implementations decode identically. The
+// legacy 24-char truncated form (produced by this package's Base32
+// before it was fixed to emit the full 26 characters) is also still
+// accepted, for reading data persisted by older versions; decoding it
+// only recovers the first 15 bytes, with uutid[15] left zero.
 const crockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+const crockfordAlphabetUpper = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordDecodeTable maps each byte to its 5-bit Crockford value, or
+// 0xFF if the byte is not a valid (already normalised) Crockford glyph.
+var crockfordDecodeTable = func() [256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = 0xff
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		table[crockfordAlphabet[i]] = byte(i)
+	}
+	return table
+}()
 
-var base32Encoder = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+// legacyBase32Encoder decodes the legacy 24-char truncated form only. It
+// is block-based (5 bytes/8 chars per block), unlike the bit-interleaved
+// ULID layout used everywhere else, because that is the layout the old,
+// buggy Base32 actually emitted.
+var legacyBase32Encoder = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
 
 func FromBase32(str string) (UUTID, error) {
-	if len(str) != 24 {
+	if len(str) != 24 && len(str) != 26 {
 		return UUTID{}, errors.New("unable to extract uutid from base32 string")
 	}
 
+	normalized := normalizeCrockford(str)
+
+	if len(normalized) == 24 {
+		return fromLegacyBase32(normalized)
+	}
+
+	var uutid UUTID
+	if err := decodeCrockford(&uutid, normalized); err != nil {
+		return UUTID{}, err
+	}
+
+	return uutid, nil
+}
+
+// fromLegacyBase32 decodes a normalised 24-char string produced by the
+// pre-fix Base32/Base32Upper, which only ever wrote 24 of the 26
+// characters needed to hold 128 bits. Padding back out to 26 characters
+// recovers the first 15 bytes; the 16th is lost and comes back zero.
+func fromLegacyBase32(normalized string) (UUTID, error) {
+	if strings.IndexFunc(normalized, func(r rune) bool {
+		return !strings.ContainsRune(crockfordAlphabet, r)
+	}) >= 0 {
+		return UUTID{}, errors.New("invalid character in base32 uutid string")
+	}
+
 	uutid := UUTID{}
-	base32Encoder.Decode(uutid[:], []byte(str[:]))
+	if _, err := legacyBase32Encoder.Decode(uutid[:], []byte(normalized+"00")); err != nil {
+		return UUTID{}, err
+	}
 
 	return uutid, nil
 }
 
+// decodeCrockford decodes the 26-character Crockford base 32 string str
+// into dst, following the same bit layout as ULID: str is treated as a
+// single 130-bit big-endian bitstream (2 padding bits followed by the
+// 128-bit value), 5 bits per character.
+func decodeCrockford(dst *UUTID, str string) error {
+	for i := 0; i < len(str); i++ {
+		if crockfordDecodeTable[str[i]] == 0xff {
+			return errors.New("invalid character in base32 uutid string")
+		}
+	}
+
+	dst[0] = crockfordDecodeTable[str[0]]<<5 | crockfordDecodeTable[str[1]]
+	dst[1] = crockfordDecodeTable[str[2]]<<3 | crockfordDecodeTable[str[3]]>>2
+	dst[2] = crockfordDecodeTable[str[3]]<<6 | crockfordDecodeTable[str[4]]<<1 | crockfordDecodeTable[str[5]]>>4
+	dst[3] = crockfordDecodeTable[str[5]]<<4 | crockfordDecodeTable[str[6]]>>1
+	dst[4] = crockfordDecodeTable[str[6]]<<7 | crockfordDecodeTable[str[7]]<<2 | crockfordDecodeTable[str[8]]>>3
+	dst[5] = crockfordDecodeTable[str[8]]<<5 | crockfordDecodeTable[str[9]]
+	dst[6] = crockfordDecodeTable[str[10]]<<3 | crockfordDecodeTable[str[11]]>>2
+	dst[7] = crockfordDecodeTable[str[11]]<<6 | crockfordDecodeTable[str[12]]<<1 | crockfordDecodeTable[str[13]]>>4
+	dst[8] = crockfordDecodeTable[str[13]]<<4 | crockfordDecodeTable[str[14]]>>1
+	dst[9] = crockfordDecodeTable[str[14]]<<7 | crockfordDecodeTable[str[15]]<<2 | crockfordDecodeTable[str[16]]>>3
+	dst[10] = crockfordDecodeTable[str[16]]<<5 | crockfordDecodeTable[str[17]]
+	dst[11] = crockfordDecodeTable[str[18]]<<3 | crockfordDecodeTable[str[19]]>>2
+	dst[12] = crockfordDecodeTable[str[19]]<<6 | crockfordDecodeTable[str[20]]<<1 | crockfordDecodeTable[str[21]]>>4
+	dst[13] = crockfordDecodeTable[str[21]]<<4 | crockfordDecodeTable[str[22]]>>1
+	dst[14] = crockfordDecodeTable[str[22]]<<7 | crockfordDecodeTable[str[23]]<<2 | crockfordDecodeTable[str[24]]>>3
+	dst[15] = crockfordDecodeTable[str[24]]<<5 | crockfordDecodeTable[str[25]]
+
+	return nil
+}
+
+// encodeCrockford encodes uutid as a 26-character Crockford base 32
+// string using alphabet, following the same bit layout as ULID: the
+// 128-bit value is treated as a single 130-bit big-endian bitstream (2
+// leading padding bits, always zero), 5 bits per character.
+func encodeCrockford(uutid UUTID, alphabet string) string {
+	var buf [26]byte
+
+	buf[0] = alphabet[(uutid[0]&0xe0)>>5]
+	buf[1] = alphabet[uutid[0]&0x1f]
+	buf[2] = alphabet[(uutid[1]&0xf8)>>3]
+	buf[3] = alphabet[(uutid[1]&0x07)<<2|(uutid[2]&0xc0)>>6]
+	buf[4] = alphabet[(uutid[2]&0x3e)>>1]
+	buf[5] = alphabet[(uutid[2]&0x01)<<4|(uutid[3]&0xf0)>>4]
+	buf[6] = alphabet[(uutid[3]&0x0f)<<1|(uutid[4]&0x80)>>7]
+	buf[7] = alphabet[(uutid[4]&0x7c)>>2]
+	buf[8] = alphabet[(uutid[4]&0x03)<<3|(uutid[5]&0xe0)>>5]
+	buf[9] = alphabet[uutid[5]&0x1f]
+	buf[10] = alphabet[(uutid[6]&0xf8)>>3]
+	buf[11] = alphabet[(uutid[6]&0x07)<<2|(uutid[7]&0xc0)>>6]
+	buf[12] = alphabet[(uutid[7]&0x3e)>>1]
+	buf[13] = alphabet[(uutid[7]&0x01)<<4|(uutid[8]&0xf0)>>4]
+	buf[14] = alphabet[(uutid[8]&0x0f)<<1|(uutid[9]&0x80)>>7]
+	buf[15] = alphabet[(uutid[9]&0x7c)>>2]
+	buf[16] = alphabet[(uutid[9]&0x03)<<3|(uutid[10]&0xe0)>>5]
+	buf[17] = alphabet[uutid[10]&0x1f]
+	buf[18] = alphabet[(uutid[11]&0xf8)>>3]
+	buf[19] = alphabet[(uutid[11]&0x07)<<2|(uutid[12]&0xc0)>>6]
+	buf[20] = alphabet[(uutid[12]&0x3e)>>1]
+	buf[21] = alphabet[(uutid[12]&0x01)<<4|(uutid[13]&0xf0)>>4]
+	buf[22] = alphabet[(uutid[13]&0x0f)<<1|(uutid[14]&0x80)>>7]
+	buf[23] = alphabet[(uutid[14]&0x7c)>>2]
+	buf[24] = alphabet[(uutid[14]&0x03)<<3|(uutid[15]&0xe0)>>5]
+	buf[25] = alphabet[uutid[15]&0x1f]
+
+	return string(buf[:])
+}
+
+// normalizeCrockford lower-cases str and rewrites the ambiguous glyphs
+// I/L to 1 and O to 0, as required by the Crockford base 32 spec.
+func normalizeCrockford(str string) string {
+	buf := make([]byte, len(str))
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		switch {
+		case c == 'I' || c == 'i' || c == 'L' || c == 'l':
+			buf[i] = '1'
+		case c == 'O' || c == 'o':
+			buf[i] = '0'
+		case c >= 'A' && c <= 'Z':
+			buf[i] = c + ('a' - 'A')
+		default:
+			buf[i] = c
+		}
+	}
+
+	return string(buf)
+}
+
 // FromBase16 returns uutid from a base 16 encoded uutid
 func FromBase16(base16 string) (UUTID, error) {
 	if len(base16) != 32 {
@@ -177,6 +250,26 @@ func FromUUID(uuid string) (UUTID, error) {
 	return UUTID{}, errors.New("unable to extract uutid")
 }
 
+// FromString returns a uutid parsed from any of the string forms produced
+// by the package: raw 16-byte binary, base64, base32 (the canonical
+// 26-char ULID form, or the legacy 24-char form), base16, or the
+// canonical dashed UUID form. The form is detected from the string
+// length.
+func FromString(str string) (UUTID, error) {
+	switch len(str) {
+	case 16:
+		return FromBytes([]byte(str))
+	case 22:
+		return FromBase64(str)
+	case 24, 26:
+		return FromBase32(str)
+	case 32, 36:
+		return FromUUID(str)
+	}
+
+	return UUTID{}, errors.New("unable to extract uutid from string")
+}
+
 // String returns uutid as a hex encoded string
 func (uutid UUTID) String() string {
 	return uutid.Base16()
@@ -189,11 +282,16 @@ func (uutid UUTID) Base64() string {
 	return string(buf[:])
 }
 
-// Base32 returns uutid as a regular base 32 encoded string
+// Base32 returns uutid as a lowercase Crockford base 32 (ULID-style)
+// encoded string.
 func (uutid UUTID) Base32() string {
-	var buf [24]byte
-	base32Encoder.Encode(buf[:], uutid[:])
-	return string(buf[:])
+	return encodeCrockford(uutid, crockfordAlphabet)
+}
+
+// Base32Upper returns uutid as an uppercase Crockford base 32 (ULID-style)
+// encoded string, matching the casing used by most ULID tooling.
+func (uutid UUTID) Base32Upper() string {
+	return encodeCrockford(uutid, crockfordAlphabetUpper)
 }
 
 // Base16 returns uutid as a regular base 16 encoded string
@@ -241,12 +339,27 @@ func (uutid UUTID) Bytes() []byte {
 	}
 }
 
+// Version returns the UUID version nibble stored in byte 6 of the UUTID.
+func (uutid UUTID) Version() int {
+	return int(uutid[6] >> 4)
+}
+
 // Time returns the timestamp of the UUTID
 func (uutid UUTID) Time() time.Time {
 	if len(uutid) < 10 {
 		return time.Time{}
 	}
 
+	switch uutid.Version() {
+	case 7:
+		return uutid.timeV7()
+	case 6:
+		return uutid.timeV6()
+	case 3, 5:
+		// v3/v5 bytes are a name-based hash; there is no timestamp to decode.
+		return time.Time{}
+	}
+
 	sec := int64(binary.BigEndian.Uint32(uutid[0:4]))
 	ns1 := int64(binary.BigEndian.Uint16(uutid[4:6]))
 	ns2 := int64(binary.BigEndian.Uint16(uutid[6:8]))
@@ -265,3 +378,156 @@ func (uutid UUTID) Time() time.Time {
 
 	return time.Unix(sec, nsec)
 }
+
+// timeV7 decodes the 48-bit big-endian millisecond Unix timestamp carried
+// in a v7 UUTID's first six bytes.
+func (uutid UUTID) timeV7() time.Time {
+	var msBuf [8]byte
+	copy(msBuf[2:], uutid[0:6])
+	ms := binary.BigEndian.Uint64(msBuf[:])
+
+	return time.UnixMilli(int64(ms)).UTC()
+}
+
+// timeV6 decodes the 60-bit Gregorian timestamp (100-ns intervals since
+// 1582-10-15 UTC) carried across the first eight bytes of a v6 UUTID.
+func (uutid UUTID) timeV6() time.Time {
+	high := uint64(binary.BigEndian.Uint32(uutid[0:4]))
+	mid := uint64(binary.BigEndian.Uint16(uutid[4:6]))
+	low := uint64(binary.BigEndian.Uint16(uutid[6:8])) & 0x0fff
+
+	ticks := high<<28 | mid<<12 | low
+
+	ns := (int64(ticks) - gregorianToUnixOffset100ns) * 100
+
+	return time.Unix(0, ns).UTC()
+}
+
+// MarshalText implements encoding.TextMarshaler, emitting the canonical
+// dashed UUID form.
+func (uutid UUTID) MarshalText() ([]byte, error) {
+	return []byte(uutid.UUID()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting any of the
+// string forms FromString handles.
+func (uutid *UUTID) UnmarshalText(text []byte) error {
+	parsed, err := FromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*uutid = parsed
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the 16 raw
+// bytes of the uutid.
+func (uutid UUTID) MarshalBinary() ([]byte, error) {
+	return uutid.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, expecting 16 raw
+// bytes.
+func (uutid *UUTID) UnmarshalBinary(data []byte) error {
+	parsed, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	*uutid = parsed
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, emitting the canonical dashed
+// UUID form as a quoted string.
+func (uutid UUTID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uutid.UUID())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a quoted string in
+// any of the formats FromString handles.
+func (uutid *UUTID) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	parsed, err := FromString(str)
+	if err != nil {
+		return err
+	}
+
+	*uutid = parsed
+
+	return nil
+}
+
+// Scan implements sql.Scanner, accepting a 16-byte []byte, a string in any
+// of the formats FromString handles, or nil (which maps to NilUUTID).
+func (uutid *UUTID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*uutid = NilUUTID
+		return nil
+	case []byte:
+		parsed, err := FromBytes(v)
+		if err != nil {
+			parsed, err = FromString(string(v))
+			if err != nil {
+				return err
+			}
+		}
+		*uutid = parsed
+		return nil
+	case string:
+		parsed, err := FromString(v)
+		if err != nil {
+			return err
+		}
+		*uutid = parsed
+		return nil
+	}
+
+	return errors.New("uutid: unable to scan value into UUTID")
+}
+
+// Value implements driver.Valuer, encoding the uutid as its canonical
+// dashed UUID string.
+func (uutid UUTID) Value() (driver.Value, error) {
+	return uutid.UUID(), nil
+}
+
+// NullUUTID represents a UUTID that may be NULL, mirroring sql.NullString.
+type NullUUTID struct {
+	UUTID UUTID
+	Valid bool
+}
+
+// Scan implements sql.Scanner.
+func (n *NullUUTID) Scan(src interface{}) error {
+	if src == nil {
+		n.UUTID, n.Valid = NilUUTID, false
+		return nil
+	}
+
+	if err := n.UUTID.Scan(src); err != nil {
+		return err
+	}
+
+	n.Valid = true
+
+	return nil
+}
+
+// Value implements driver.Valuer, mapping a not-Valid NullUUTID to SQL
+// NULL.
+func (n NullUUTID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	return n.UUTID.Value()
+}
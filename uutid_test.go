@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"log"
 	"testing"
 	"time"
@@ -127,7 +128,10 @@ func TestNewWithCryptoRand(t *testing.T) {
 }
 
 func TestNewWithVersion(t *testing.T) {
-	SetVersion(5)
+	// Versions 3, 5, and 6 have dedicated, non-generic layouts and are
+	// rejected by SetVersion (see TestSetVersionRejectsDedicatedVersions);
+	// use version 2 here to exercise the generic dispatch.
+	SetVersion(2)
 
 	now := time.Now().Truncate(0)
 
@@ -150,6 +154,240 @@ func TestNewWithVersion(t *testing.T) {
 	SetVersion(4)
 }
 
+func TestSetVersionRejectsDedicatedVersions(t *testing.T) {
+	for _, v := range []int{3, 5, 6} {
+		if err := SetVersion(v); err == nil {
+			t.Fatalf("expected SetVersion(%d) to fail, versions 3/5/6 have dedicated constructors", v)
+		}
+	}
+}
+
+func TestWithVersionIgnoresDedicatedVersions(t *testing.T) {
+	for _, v := range []int{3, 5, 6} {
+		g := NewGenerator(WithVersion(v))
+		if g.version == v {
+			t.Fatalf("expected WithVersion(%d) to be ignored, versions 3/5/6 have dedicated constructors", v)
+		}
+	}
+}
+
+func TestNewV7(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	uutid := NewV7()
+	if uutid == NilUUTID {
+		t.Fatal("expected uutid to not be nil")
+	}
+
+	if uutid.Version() != 7 {
+		t.Fatalf("expected version to be 7, got: %d", uutid.Version())
+	}
+
+	if uutid[8]&0xc0 != 0x80 {
+		t.Fatalf("expected variant bits to be 10, got: %08b", uutid[8])
+	}
+
+	uutidTime := uutid.Time()
+	diff := uutidTime.Sub(now)
+	if diff < 0 || diff > time.Millisecond {
+		t.Fatalf("expected UUTID time to be within 1ms of now, got diff: %s", diff)
+	}
+}
+
+func TestNewV7WithTime(t *testing.T) {
+	// The monotonic counter clamps to the last observed millisecond, so use
+	// a time comfortably ahead of any previously generated v7 UUTID in this
+	// process to keep the test deterministic.
+	testTime := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+
+	uutid := NewV7WithTime(testTime)
+	if uutid == NilUUTID {
+		t.Fatal("expected uutid to not be nil")
+	}
+
+	uutidTime := uutid.Time()
+	if !uutidTime.Equal(testTime) {
+		t.Fatalf("expected UUTID time to equal %s, got: %s", testTime, uutidTime)
+	}
+}
+
+func TestNewV7Monotonic(t *testing.T) {
+	testTime := time.Now().Add(2 * time.Hour).Truncate(time.Millisecond)
+
+	first := NewV7WithTime(testTime)
+	second := NewV7WithTime(testTime)
+
+	firstCounter := uint16(first[6]&0x0f)<<8 | uint16(first[7])
+	secondCounter := uint16(second[6]&0x0f)<<8 | uint16(second[7])
+
+	if secondCounter <= firstCounter {
+		t.Fatalf("expected monotonic counter to increase for calls in the same millisecond, first: %d, second: %d", firstCounter, secondCounter)
+	}
+
+	// A later timestamp resets the counter but must still sort after first/second
+	later := NewV7WithTime(testTime.Add(time.Millisecond))
+	if bytes.Compare(second[:], later[:]) >= 0 {
+		t.Fatalf("expected later UUTID to sort after second UUTID")
+	}
+}
+
+func TestNewV6(t *testing.T) {
+	now := time.Now().Truncate(0)
+
+	uutid := NewV6()
+	if uutid == NilUUTID {
+		t.Fatal("expected uutid to not be nil")
+	}
+
+	if uutid.Version() != 6 {
+		t.Fatalf("expected version to be 6, got: %d", uutid.Version())
+	}
+
+	if uutid[8]&0xc0 != 0x80 {
+		t.Fatalf("expected variant bits to be 10, got: %08b", uutid[8])
+	}
+
+	uutidTime := uutid.Time()
+	diff := uutidTime.Sub(now)
+	if diff < -100*time.Nanosecond || diff > time.Millisecond {
+		t.Fatalf("expected UUTID time to be close to now, got diff: %s", diff)
+	}
+}
+
+func TestNewV6WithTime(t *testing.T) {
+	testTime := time.Date(2024, 3, 15, 9, 30, 0, 123400, time.UTC)
+
+	uutid := NewV6WithTime(testTime)
+	if uutid == NilUUTID {
+		t.Fatal("expected uutid to not be nil")
+	}
+
+	uutidTime := uutid.Time()
+	if !uutidTime.Equal(testTime) {
+		t.Fatalf("expected UUTID time to equal %s, got: %s", testTime, uutidTime)
+	}
+}
+
+func TestNewV6SortsByTime(t *testing.T) {
+	earlier := NewV6WithTime(time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC))
+	later := NewV6WithTime(time.Date(2024, 3, 15, 9, 30, 1, 0, time.UTC))
+
+	if bytes.Compare(earlier[:], later[:]) >= 0 {
+		t.Fatalf("expected earlier UUTID to sort before later UUTID")
+	}
+}
+
+func TestSetNodeID(t *testing.T) {
+	node := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+	SetNodeID(node)
+
+	uutid := NewV6()
+	if !bytes.Equal(uutid[10:], node) {
+		t.Fatalf("expected node ID to be %x, got: %x", node, uutid[10:])
+	}
+}
+
+func TestGeneratorIndependentConfiguration(t *testing.T) {
+	fixed := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+
+	g7 := NewGenerator(WithVersion(7), WithClock(func() time.Time { return fixed }))
+	g4 := NewGenerator(WithVersion(4), WithClock(func() time.Time { return fixed }))
+
+	v7 := g7.New()
+	v4 := g4.New()
+
+	if v7.Version() != 7 {
+		t.Fatalf("expected g7 to produce version 7, got: %d", v7.Version())
+	}
+	if v4.Version() == 7 {
+		t.Fatal("expected g4 to be unaffected by g7's configuration")
+	}
+}
+
+func TestGeneratorWithNodeID(t *testing.T) {
+	node := []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	g := NewGenerator(WithNodeID(node))
+
+	uutid := g.NewV6()
+	if !bytes.Equal(uutid[10:], node) {
+		t.Fatalf("expected node ID to be %x, got: %x", node, uutid[10:])
+	}
+}
+
+func TestGeneratorWithMonotonicDisabled(t *testing.T) {
+	fixed := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	g := NewGenerator(WithVersion(7), WithClock(func() time.Time { return fixed }), WithMonotonic(false))
+
+	first := g.NewV7()
+	second := g.NewV7()
+
+	if first[6]&0x0f != 0 || first[7] != 0 {
+		t.Fatalf("expected counter to be 0 with monotonic disabled, got byte6: %08b byte7: %08b", first[6], first[7])
+	}
+	if second[6]&0x0f != 0 || second[7] != 0 {
+		t.Fatalf("expected counter to be 0 with monotonic disabled, got byte6: %08b byte7: %08b", second[6], second[7])
+	}
+}
+
+func TestDefaultGeneratorBackedByPackageFuncs(t *testing.T) {
+	SetVersion(7)
+	defer SetVersion(4)
+
+	uutid := New()
+	if uutid.Version() != 7 {
+		t.Fatalf("expected New() to respect SetVersion(7), got version: %d", uutid.Version())
+	}
+}
+
+func TestNewV3Deterministic(t *testing.T) {
+	first := NewV3(NamespaceDNS, []byte("example.com"))
+	second := NewV3(NamespaceDNS, []byte("example.com"))
+
+	if first != second {
+		t.Fatalf("expected NewV3 to be deterministic for the same ns/name, got: %s and %s", first, second)
+	}
+
+	if first.Version() != 3 {
+		t.Fatalf("expected version to be 3, got: %d", first.Version())
+	}
+	if first[8]&0xc0 != 0x80 {
+		t.Fatalf("expected variant bits to be 10, got: %08b", first[8])
+	}
+
+	other := NewV3(NamespaceDNS, []byte("example.org"))
+	if first == other {
+		t.Fatal("expected different names to produce different UUTIDs")
+	}
+}
+
+func TestNewV5Deterministic(t *testing.T) {
+	first := NewV5(NamespaceURL, []byte("https://example.com"))
+	second := NewV5(NamespaceURL, []byte("https://example.com"))
+
+	if first != second {
+		t.Fatalf("expected NewV5 to be deterministic for the same ns/name, got: %s and %s", first, second)
+	}
+
+	if first.Version() != 5 {
+		t.Fatalf("expected version to be 5, got: %d", first.Version())
+	}
+	if first[8]&0xc0 != 0x80 {
+		t.Fatalf("expected variant bits to be 10, got: %08b", first[8])
+	}
+}
+
+func TestV3V5TimeIsZero(t *testing.T) {
+	v3 := NewV3(NamespaceDNS, []byte("example.com"))
+	if !v3.Time().IsZero() {
+		t.Fatalf("expected v3 UUTID.Time() to be zero, got: %s", v3.Time())
+	}
+
+	v5 := NewV5(NamespaceDNS, []byte("example.com"))
+	if !v5.Time().IsZero() {
+		t.Fatalf("expected v5 UUTID.Time() to be zero, got: %s", v5.Time())
+	}
+}
+
 func TestFromBase64(t *testing.T) {
 	// uutid := New()
 	// expected := uutid.Base64()
@@ -241,6 +479,206 @@ func TestFromString(t *testing.T) {
 	}
 }
 
+func TestFromBase32(t *testing.T) {
+	expected := New()
+
+	lower := expected.Base32()
+	actual, err := FromBase32(lower)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected[:], actual[:]) {
+		t.Fatalf("actual and expected base32 doesn't match.\nexpected: %x, got: %x", expected, actual)
+	}
+
+	upper := expected.Base32Upper()
+	actual, err = FromBase32(upper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected[:], actual[:]) {
+		t.Fatalf("actual and expected uppercase base32 doesn't match.\nexpected: %x, got: %x", expected, actual)
+	}
+}
+
+func TestFromBase32AmbiguousGlyphs(t *testing.T) {
+	normalized := normalizeCrockford("ILOilo01")
+	if normalized != "11011001" {
+		t.Fatalf(`expected "ILOilo01" to normalize to "11011001", got: %s`, normalized)
+	}
+}
+
+func TestFromBase32Invalid(t *testing.T) {
+	if _, err := FromBase32("not-a-valid-base32-uutid!"); err == nil {
+		t.Fatal("expected an error for an invalid base32 string")
+	}
+}
+
+func TestBase32MatchesULIDBitLayout(t *testing.T) {
+	max := UUTID{}
+	for i := range max {
+		max[i] = 0xff
+	}
+
+	if got := max.Base32Upper(); got != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Fatalf("expected max UUTID to encode to the canonical max ULID, got: %s", got)
+	}
+
+	decoded, err := FromBase32("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(max[:], decoded[:]) {
+		t.Fatalf("expected canonical max ULID to decode back to all 0xff, got: %x", decoded)
+	}
+
+	zero, err := FromBase32("00000000000000000000000000"[:26])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(NilUUTID[:], zero[:]) {
+		t.Fatalf("expected all-zero ULID to decode to NilUUTID, got: %x", zero)
+	}
+}
+
+func TestFromBase32LegacyTruncatedForm(t *testing.T) {
+	expected := New()
+
+	// Reproduce the 24-char string the pre-fix, block-based Base32 used
+	// to emit for this uutid (it wrote only the first 24 of the 26
+	// characters its own encoder produced).
+	var buf [26]byte
+	legacyBase32Encoder.Encode(buf[:], expected[:])
+	truncated := string(buf[:24])
+
+	actual, err := FromBase32(truncated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected[:15], actual[:15]) {
+		t.Fatalf("expected first 15 bytes to round-trip through the legacy 24-char form, expected: %x, got: %x", expected[:15], actual[:15])
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	expected := New()
+
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `"`+expected.UUID()+`"` {
+		t.Fatalf(`expected JSON to be %q, got: %s`, expected.UUID(), data)
+	}
+
+	var actual UUTID
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != expected {
+		t.Fatalf("expected JSON round-trip to produce the same UUTID, expected: %s, got: %s", expected, actual)
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	expected := New()
+
+	text, err := expected.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual UUTID
+	if err := actual.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != expected {
+		t.Fatalf("expected text round-trip to produce the same UUTID, expected: %s, got: %s", expected, actual)
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	expected := New()
+
+	// pgx and similar drivers pass UUID binary params as a raw 16-byte slice.
+	data, err := expected.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 16 {
+		t.Fatalf("expected 16 raw bytes, got: %d", len(data))
+	}
+
+	var actual UUTID
+	if err := actual.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != expected {
+		t.Fatalf("expected binary round-trip to produce the same UUTID, expected: %s, got: %s", expected, actual)
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	expected := New()
+
+	value, err := expected.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual UUTID
+	if err := actual.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Fatalf("expected Scan(Value()) to round-trip, expected: %s, got: %s", expected, actual)
+	}
+
+	if err := actual.Scan(expected.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Fatalf("expected Scan([]byte) to round-trip, expected: %s, got: %s", expected, actual)
+	}
+
+	if err := actual.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if actual != NilUUTID {
+		t.Fatalf("expected Scan(nil) to produce NilUUTID, got: %s", actual)
+	}
+}
+
+func TestNullUUTID(t *testing.T) {
+	var n NullUUTID
+	if err := n.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid {
+		t.Fatal("expected Valid to be false after scanning nil")
+	}
+
+	value, err := n.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != nil {
+		t.Fatalf("expected a not-Valid NullUUTID to map to SQL NULL, got: %v", value)
+	}
+
+	expected := New()
+	if err := n.Scan(expected.UUID()); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.UUTID != expected {
+		t.Fatalf("expected NullUUTID to scan %s, got valid=%v uutid=%s", expected, n.Valid, n.UUTID)
+	}
+}
+
 func TestAllCombosOnSameUUTID(t *testing.T) {
 	testTime := time.Date(2021, 1, 17, 1, 5, 10, 123456900, time.UTC).Truncate(0)
 	testUUTID := NewWithTime(testTime)